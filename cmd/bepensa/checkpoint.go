@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"tmp/seda-orderdata-go/internal/client"
+	"tmp/seda-orderdata-go/internal/storage"
+)
+
+// PartStatus is the ingestion state of a single part file.
+type PartStatus string
+
+const (
+	PartStatusDone   PartStatus = "done"
+	PartStatusFailed PartStatus = "failed"
+)
+
+// PartRecord is one entry in the checkpoint manifest, recording the outcome
+// of ingesting a single part.
+type PartRecord struct {
+	SourceFile  string     `json:"sourceFile"`
+	PartName    string     `json:"partName"`
+	ExecutionId string     `json:"executionId,omitempty"`
+	Status      PartStatus `json:"status"`
+	Error       string     `json:"error,omitempty"`
+
+	// ExecutionState is the terminal state of ExecutionId, populated only
+	// when the command is run with --wait.
+	ExecutionState client.ExecutionState `json:"executionState,omitempty"`
+}
+
+// Checkpoint is a JSON manifest, stored alongside the part files, recording
+// which parts have already been ingested so that re-running the command
+// skips work that already succeeded.
+type Checkpoint struct {
+	store  storage.Storage
+	bucket string
+	name   string
+
+	mu      sync.Mutex
+	records map[string]PartRecord // keyed by PartName
+}
+
+// loadCheckpoint reads the checkpoint manifest at bucket/name, returning an
+// empty checkpoint if none exists yet.
+func loadCheckpoint(ctx context.Context, store storage.Storage, bucket, name string) (*Checkpoint, error) {
+	cp := &Checkpoint{store: store, bucket: bucket, name: name, records: map[string]PartRecord{}}
+
+	r, err := store.NewReader(ctx, bucket, name)
+	if errors.Is(err, storage.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var records []PartRecord
+	if err = json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		cp.records[rec.PartName] = rec
+	}
+	return cp, nil
+}
+
+// Done reports whether partName has already been ingested successfully.
+func (cp *Checkpoint) Done(partName string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.records[partName].Status == PartStatusDone
+}
+
+// Get returns the recorded outcome for partName, if any.
+func (cp *Checkpoint) Get(partName string) (PartRecord, bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	rec, ok := cp.records[partName]
+	return rec, ok
+}
+
+// Record saves rec and persists the updated manifest to the checkpoint
+// object, so that progress survives a crash or restart. The lock is held
+// across the persist itself, since the ingest stage calls Record from many
+// workers concurrently and an unlocked write could land out of order,
+// letting an older snapshot overwrite a newer one and silently lose a
+// completed part.
+func (cp *Checkpoint) Record(ctx context.Context, rec PartRecord) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.records[rec.PartName] = rec
+	records := make([]PartRecord, 0, len(cp.records))
+	for _, r := range cp.records {
+		records = append(records, r)
+	}
+
+	w, err := cp.store.NewWriter(ctx, cp.bucket, cp.name)
+	if err != nil {
+		return err
+	}
+	if err = json.NewEncoder(w).Encode(records); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
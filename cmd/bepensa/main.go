@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pkg/errors"
@@ -13,36 +15,23 @@ import (
 	"strings"
 	"time"
 	"tmp/seda-orderdata-go/internal/client"
-
-	"cloud.google.com/go/storage"
-	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
+	"tmp/seda-orderdata-go/internal/config"
+	"tmp/seda-orderdata-go/internal/storage"
 )
 
-const AuthFile = "bepensa.json"
-const MaxFileSize = 10 * 1 << 20 // 10 MB
-
-const InBucket = "bucket_rmscm02056_yalo"
-const OutBucket = "cmrc-integrations"
-
-const RootFolder = "mx_sellout"
-
-const (
-	TemplateName   = "bepensa-order"
-	BotId          = "bepensa-mx-prd"
-	StorefrontName = "bepensa-mx-b2b"
-	KeyExpression  = "Record.get('id')"
-)
+// DefaultConfigPath is used when --config is not given.
+const DefaultConfigPath = "configs/bepensa.yaml"
 
 /*
-Bepensa order integration script
--------------------------------
+Customer order integration script
+----------------------------------
 
-This program looks for CSV files in a customer-supplied GCS bucket which match today's date. Matching files are split
-into smaller parts, filtering out rows which should not be in the final data set. (See "filtering" below.) The parts
-are then uploaded to the internal Yalo bucket, and an integration is created.
+This program looks for CSV files in a customer-supplied bucket which match today's date. Matching files are split
+into smaller parts, filtering out rows which should not be in the final data set, as described by the customer's
+config descriptor (see internal/config). The parts are then uploaded to the internal Yalo bucket, and an
+integration is created.
 
-Example data files for Bepsensa:
+Example data files for Bepensa, whose descriptor is configs/bepensa.yaml:
 
 gs://bucket_rmscm02056_yalo/mx_sellout/20230801-cubo-ventas-40d-001.csv
 gs://bucket_rmscm02056_yalo/mx_sellout/20230801-cubo-ventas-40d-002.csv
@@ -57,14 +46,17 @@ Usage
 - EXECUTIONS_TOKEN
 - TEMPLATES_TOKEN
 
-2. Make sure you have a file called "bepensa.json" with the credentials to the Bepensa bucket. This file should
-Storage.Objects.Read and Storage.Objects.List permissions.
+2. Make sure the file named by the descriptor's inAuthFile (e.g. "bepensa.json") exists, with credentials to the
+customer's bucket. This file should have Storage.Objects.Read and Storage.Objects.List permissions.
+
+3. Run the program, pointing it at the customer's descriptor
 
-3. Run the program
+go run ./cmd/bepensa --config configs/bepensa.yaml
 
-go run cmd/bepensa/main.go
+Onboarding a new customer requires no code changes: add a new descriptor under configs/ and pass its path to
+--config.
 
-The output of the program will be the execution IDs for each part that matches today's date.
+The output of the program is a JSON report of the parts ingested, including their execution IDs.
 
 */
 
@@ -72,6 +64,21 @@ type Config struct {
 	ApiUrl          string `split_words:"true" default:"https://api-ww-us-001.yalochat.com/commerce"`
 	TemplatesToken  string `split_words:"true"`
 	ExecutionsToken string `split_words:"true"`
+
+	// StorageInDriver and StorageOutDriver select the Storage implementation
+	// used to read source files and write parts + the checkpoint manifest,
+	// respectively. Defaults to GCS, this project's original backend.
+	StorageInDriver  string `split_words:"true" default:"gcs"`
+	StorageOutDriver string `split_words:"true" default:"gcs"`
+
+	// StorageRegion is used by the s3 and oss drivers, StorageEndpoint by the
+	// s3 driver only.
+	StorageRegion   string `split_words:"true"`
+	StorageEndpoint string `split_words:"true"`
+
+	// StorageInRootDir and StorageOutRootDir are used by the local driver.
+	StorageInRootDir  string `split_words:"true"`
+	StorageOutRootDir string `split_words:"true"`
 }
 
 func templatesUrl(cfg *Config) string {
@@ -85,57 +92,48 @@ func executionsUrl(cfg *Config) string {
 type File struct {
 	Bucket string
 	Name   string
+
+	// Source is the name of the file this one was split from. It is empty
+	// for files returned by findFiles, which are themselves sources.
+	Source string
 }
 
 func findFiles(
 	ctx context.Context,
-	inClient *storage.Client,
-	bucket string,
+	in storage.Storage,
+	desc *config.Descriptor,
 ) ([]File, error) {
-	var names []string
-
-	t := time.Now().Format("20060102")
-	query := &storage.Query{
-		Prefix:    RootFolder + "/",
-		MatchGlob: "**/" + t + "*.csv",
+	objects, err := in.List(ctx, desc.InBucket, &storage.Query{
+		Prefix:    desc.RootFolder + "/",
+		MatchGlob: desc.Glob(time.Now()),
 		Delimiter: "/",
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	bkt := inClient.Bucket(bucket)
-
-	it := bkt.Objects(ctx, query)
-	for {
-		attrs, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			panic(err)
-		}
-		names = append(names, attrs.Name)
-	}
-	files := make([]File, len(names))
-	for i, name := range names {
+	files := make([]File, len(objects))
+	for i, obj := range objects {
 		files[i] = File{
-			Bucket: bucket,
-			Name:   name,
+			Bucket: desc.InBucket,
+			Name:   obj.Name,
 		}
 	}
 	return files, nil
 }
 
-// splitFile gets the file using the inClient and splits it into pieces
+// splitFile gets the file using in and splits it into pieces written to out, keeping only the rows that
+// satisfy desc's filters.
 func splitFile(
 	ctx context.Context,
-	inClient *storage.Client,
-	outClient *storage.Client,
+	in storage.Storage,
+	out storage.Storage,
+	desc *config.Descriptor,
 	file File,
 ) ([]File, error) {
-	obj := inClient.Bucket(file.Bucket).Object(file.Name)
-
 	var files []File
 
-	r, err := obj.NewReader(ctx)
+	r, err := in.NewReader(ctx, file.Bucket, file.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -148,9 +146,10 @@ func splitFile(
 		return nil, err
 	}
 
-	var wc *storage.Writer
+	var wc io.WriteCloser
 	var writer *csv.Writer
-	var bytesWritten int
+	var partName string
+	var bytesWritten int64
 
 	for i := 0; ; i++ {
 		record, err := reader.Read()
@@ -164,23 +163,22 @@ func splitFile(
 		}
 
 		// Check if it's time to create a new part file.
-		if wc == nil || bytesWritten > MaxFileSize {
+		if wc == nil || bytesWritten > desc.MaxFileSize {
 			if wc != nil {
 				err = wc.Close()
 				if err == nil {
-					files = append(files, File{OutBucket, wc.Name})
+					files = append(files, File{Bucket: desc.OutBucket, Name: partName, Source: file.Name})
 				} else {
 					return files, err
 				}
 			}
 
-			partFileName := fmt.Sprintf(file.Name+"_part_%06d.csv", i)
-
-			objPart := outClient.Bucket(OutBucket).Object(partFileName)
+			partName = fmt.Sprintf(file.Name+"_part_%06d.csv", i)
 
-			// Read it back.
-			wc = objPart.NewWriter(ctx)
-			wc.ContentType = "text/csv"
+			wc, err = out.NewWriter(ctx, desc.OutBucket, partName)
+			if err != nil {
+				return files, err
+			}
 
 			writer = csv.NewWriter(wc)
 			err = writer.Write(header)
@@ -191,13 +189,11 @@ func splitFile(
 			bytesWritten = 0
 		}
 
-		// For Bepensa, row 5 is the quantity.
-		row5, err := strconv.ParseFloat(record[5], 64)
+		keep, err := config.MatchAll(desc.Filters, header, record)
 		if err != nil {
-			return files, errors.Wrap(err, "could not convert row 5 to float")
+			return files, errors.WithMessage(err, "could not apply row filters")
 		}
-		// Avoid strict equality due to floating point errors
-		if row5-0. > 0.0001 {
+		if keep {
 			err = writer.Write(record)
 			if err != nil {
 				return files, err
@@ -205,13 +201,16 @@ func splitFile(
 		}
 		writer.Flush()
 		// TODO check Err()
-		if row5-0. > 0.0001 {
-			bytesWritten += len([]byte(strings.Join(record, ","))) + len([]byte("\n"))
+		if keep {
+			bytesWritten += int64(len([]byte(strings.Join(record, ","))) + len([]byte("\n")))
 		}
 	}
 
 	if wc != nil {
-		wc.Close()
+		if err = wc.Close(); err != nil {
+			return files, err
+		}
+		files = append(files, File{Bucket: desc.OutBucket, Name: partName, Source: file.Name})
 	}
 	return files, nil
 }
@@ -220,16 +219,17 @@ func ingestFile(
 	ctx context.Context,
 	tmplClient *client.TemplatesClient,
 	execClient *client.ExecutionsClient,
+	desc *config.Descriptor,
 	file File,
 ) (string, error) {
 	args := map[string]string{
-		"storefrontName": StorefrontName,
-		"botId":          BotId,
-		"keyExpression":  KeyExpression,
-		"bucket":         file.Bucket,
-		"file":           file.Name,
+		"bucket": file.Bucket,
+		"file":   file.Name,
+	}
+	for k, v := range desc.TemplateArgs {
+		args[k] = v
 	}
-	tmpl, err := tmplClient.RenderTemplate(ctx, TemplateName, args)
+	tmpl, err := tmplClient.RenderTemplate(ctx, desc.TemplateName, args)
 	if err != nil {
 		return "", err
 	}
@@ -238,25 +238,36 @@ func ingestFile(
 }
 
 func main() {
+	configPath := flag.String("config", DefaultConfigPath, "path to the customer's ingestion config (YAML or JSON)")
+	workers := flag.Int("workers", workerCount(), "number of parts to split and ingest concurrently")
+	wait := flag.Bool("wait", false, "block until every created execution finishes, and report pass/fail per part")
+	flag.Parse()
+
 	ctx := context.Background()
 
 	cfg := &Config{}
 	envconfig.Process("", cfg)
 
-	f, err := os.Open(AuthFile)
+	desc, err := config.Load(*configPath)
 	if err != nil {
 		panic(err)
 	}
-	defer f.Close()
 
-	authJson, err := io.ReadAll(f)
-
-	inClient, err := storage.NewClient(ctx, option.WithCredentialsJSON(authJson))
+	inClient, err := storage.New(ctx, storage.Driver(cfg.StorageInDriver), storage.Options{
+		CredentialsFile: desc.InAuthFile,
+		Region:          cfg.StorageRegion,
+		Endpoint:        cfg.StorageEndpoint,
+		RootDir:         cfg.StorageInRootDir,
+	})
 	if err != nil {
 		panic(err)
 	}
 
-	outClient, err := storage.NewClient(ctx)
+	outClient, err := storage.New(ctx, storage.Driver(cfg.StorageOutDriver), storage.Options{
+		Region:   cfg.StorageRegion,
+		Endpoint: cfg.StorageEndpoint,
+		RootDir:  cfg.StorageOutRootDir,
+	})
 	if err != nil {
 		panic(err)
 	}
@@ -266,47 +277,56 @@ func main() {
 		httpClient,
 		client.WithTemplatesEndpoint(templatesUrl(cfg)),
 		client.WithTemplatesToken(cfg.TemplatesToken))
+	if err != nil {
+		panic(err)
+	}
 
 	execClient, err := client.NewExecutionsClient(
 		httpClient,
 		client.WithExecutionsEndpoint(executionsUrl(cfg)),
 		client.WithExecutionsToken(cfg.ExecutionsToken))
+	if err != nil {
+		panic(err)
+	}
 
+	files, err := findFiles(ctx, inClient, desc)
 	if err != nil {
 		panic(err)
 	}
 
-	files, err := findFiles(ctx, inClient, InBucket)
+	cp, err := loadCheckpoint(ctx, outClient, desc.OutBucket, desc.Checkpoint())
 	if err != nil {
 		panic(err)
 	}
 
-	filesExecs := make([][]string, len(files))
-	var i, j int
-	var fi, fp File
-Files:
-	for i, fi = range files {
-		var parts []File
-		parts, err = splitFile(ctx, inClient, outClient, fi)
-		fmt.Println(fi.Name + ": " + strconv.Itoa(len(parts)) + " parts")
-		if err != nil {
-			break Files
-		}
-		filesExecs[i] = make([]string, len(parts))
-		for j, fp = range parts {
-			var id string
-			id, err = ingestFile(ctx, tmplClient, execClient, fp)
-			if err != nil {
-				break Files
-			}
-			filesExecs[i][j] = id
-		}
+	report, err := runPipeline(ctx, inClient, outClient, tmplClient, execClient, desc, cp, files, *workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ingestion failed: %s\n", err)
+	}
+
+	if *wait {
+		waitForReport(ctx, execClient, cp, report)
+		succeeded, failed := executionSummary(report)
+		fmt.Fprintf(os.Stderr, "executions finished: %d succeeded, %d failed\n", succeeded, failed)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(report); encErr != nil {
+		panic(encErr)
 	}
 	if err != nil {
-		fmt.Printf("Error ingesting\n- file: %s\n- part: %s\n\nerror: %s", fi, fp, err)
+		os.Exit(1)
 	}
-	for n, k := range filesExecs {
-		fmt.Println(files[n])
-		fmt.Println(k)
+}
+
+// workerCount returns the --workers default: the WORKERS environment
+// variable if set to a positive integer, otherwise defaultWorkers.
+func workerCount() int {
+	if v := os.Getenv("WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
 	}
+	return defaultWorkers
 }
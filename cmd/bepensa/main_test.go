@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"tmp/seda-orderdata-go/internal/config"
+	"tmp/seda-orderdata-go/internal/storage"
+)
+
+func TestSplitFile(t *testing.T) {
+	ctx := context.Background()
+	fake := storage.NewFake()
+
+	fake.Seed("in-bucket", "orders.csv", []byte(
+		"id,status\n"+
+			"1,keep\n"+
+			"2,drop\n"+
+			"3,keep\n",
+	))
+
+	desc := &config.Descriptor{
+		OutBucket:   "out-bucket",
+		MaxFileSize: 1 << 20,
+		Filters: []config.Filter{
+			{Column: "status", Op: config.FilterOpEq, Value: "keep"},
+		},
+	}
+
+	parts, err := splitFile(ctx, fake, fake, desc, File{Bucket: "in-bucket", Name: "orders.csv"})
+	if err != nil {
+		t.Fatalf("splitFile returned error: %v", err)
+	}
+	if len(parts) != 1 {
+		t.Fatalf("got %d parts, want 1 (the single, never-rotated part must still be returned)", len(parts))
+	}
+	if parts[0].Source != "orders.csv" {
+		t.Errorf("part Source = %q, want %q", parts[0].Source, "orders.csv")
+	}
+
+	contents, ok := fake.Get(desc.OutBucket, parts[0].Name)
+	if !ok {
+		t.Fatalf("part %q was not written to %q", parts[0].Name, desc.OutBucket)
+	}
+
+	got := string(contents)
+	if !strings.Contains(got, "1,keep") || !strings.Contains(got, "3,keep") {
+		t.Errorf("part contents missing kept rows: %q", got)
+	}
+	if strings.Contains(got, "2,drop") {
+		t.Errorf("part contents include filtered-out row: %q", got)
+	}
+}
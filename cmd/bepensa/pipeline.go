@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"tmp/seda-orderdata-go/internal/client"
+	"tmp/seda-orderdata-go/internal/config"
+	"tmp/seda-orderdata-go/internal/storage"
+)
+
+// defaultWorkers is the pipeline parallelism used when neither --workers nor
+// the WORKERS environment variable is set.
+const defaultWorkers = 4
+
+// Report is the structured summary of an ingestion run, emitted as JSON in
+// place of the old line-by-line console output.
+type Report struct {
+	Parts []PartRecord `json:"parts"`
+}
+
+// runPipeline streams files through concurrent stages - splitting into
+// parts, then template rendering plus execution creation per part - each
+// bounded by workers, and
+// checkpoints each part as it completes so a re-run can skip parts already
+// ingested.
+func runPipeline(
+	ctx context.Context,
+	inClient, outClient storage.Storage,
+	tmplClient *client.TemplatesClient,
+	execClient *client.ExecutionsClient,
+	desc *config.Descriptor,
+	cp *Checkpoint,
+	files []File,
+	workers int,
+) (*Report, error) {
+	if workers < 1 {
+		workers = defaultWorkers
+	}
+
+	fileCh := make(chan File)
+	partCh := make(chan File, workers)
+	resultCh := make(chan PartRecord, workers)
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Stage 1: feed source files into the pipeline.
+	g.Go(func() error {
+		defer close(fileCh)
+		for _, f := range files {
+			select {
+			case fileCh <- f:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	// Stage 2: split each source file into parts and upload them.
+	var splitGroup errgroup.Group
+	for i := 0; i < workers; i++ {
+		splitGroup.Go(func() error {
+			for f := range fileCh {
+				parts, err := splitFile(ctx, inClient, outClient, desc, f)
+				if err != nil {
+					return err
+				}
+				for _, p := range parts {
+					select {
+					case partCh <- p:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		defer close(partCh)
+		return splitGroup.Wait()
+	})
+
+	// Stage 3: render the template and create an execution for each part,
+	// skipping parts the checkpoint already marked done.
+	var ingestGroup errgroup.Group
+	for i := 0; i < workers; i++ {
+		ingestGroup.Go(func() error {
+			for p := range partCh {
+				if rec, ok := cp.Get(p.Name); ok && rec.Status == PartStatusDone {
+					select {
+					case resultCh <- rec:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					continue
+				}
+
+				rec := PartRecord{SourceFile: p.Source, PartName: p.Name, Status: PartStatusDone}
+				id, err := ingestFile(ctx, tmplClient, execClient, desc, p)
+				if err != nil {
+					rec.Status = PartStatusFailed
+					rec.Error = err.Error()
+				}
+				rec.ExecutionId = id
+
+				if cerr := cp.Record(ctx, rec); cerr != nil {
+					return cerr
+				}
+
+				select {
+				case resultCh <- rec:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		defer close(resultCh)
+		return ingestGroup.Wait()
+	})
+
+	// Collect results concurrently so the bounded resultCh never blocks the
+	// ingest stage while g.Wait() is pending below.
+	var report Report
+	var mu sync.Mutex
+	g.Go(func() error {
+		for rec := range resultCh {
+			mu.Lock()
+			report.Parts = append(report.Parts, rec)
+			mu.Unlock()
+		}
+		return nil
+	})
+
+	err := g.Wait()
+	return &report, err
+}
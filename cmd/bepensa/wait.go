@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+
+	"tmp/seda-orderdata-go/internal/client"
+)
+
+// waitForReport blocks until every execution created in report reaches a
+// terminal state, annotating each part with its final ExecutionState and
+// re-persisting the checkpoint, so a Cloud Function invocation can treat
+// the run as truly complete rather than fire-and-forget.
+func waitForReport(ctx context.Context, execClient *client.ExecutionsClient, cp *Checkpoint, report *Report) {
+	for i := range report.Parts {
+		rec := &report.Parts[i]
+		if rec.ExecutionId == "" {
+			continue
+		}
+
+		status, err := execClient.WaitForExecution(ctx, rec.ExecutionId)
+		if err != nil {
+			rec.Error = err.Error()
+			continue
+		}
+		rec.ExecutionState = status.State
+
+		if cerr := cp.Record(ctx, *rec); cerr != nil {
+			rec.Error = cerr.Error()
+		}
+	}
+}
+
+// executionSummary counts how many parts in report finished with their
+// execution succeeded, and how many with it failed or canceled.
+func executionSummary(report *Report) (succeeded, failed int) {
+	for _, p := range report.Parts {
+		switch p.ExecutionState {
+		case client.ExecutionStateSucceeded:
+			succeeded++
+		case client.ExecutionStateFailed, client.ExecutionStateCanceled:
+			failed++
+		}
+	}
+	return succeeded, failed
+}
@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	stderrs "errors"
 )
@@ -16,9 +19,119 @@ import (
 var templatesApiError = stderrs.New("templates API error")
 var executionsApiError = stderrs.New("executions API error")
 
+// retryConfig controls the exponential backoff retry behavior shared by the
+// executions and templates clients.
+type retryConfig struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// defaultRetryConfig performs the request once, with no retries.
+func defaultRetryConfig() retryConfig {
+	return retryConfig{maxAttempts: 1}
+}
+
+// isRetryableStatus reports whether an HTTP status code should be retried:
+// rate limiting and server errors are transient, everything else is not.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// retryDelay returns how long to wait before the given attempt (0-indexed),
+// honoring a server-provided Retry-After if present and otherwise computing
+// a jittered exponential backoff capped at cfg.maxDelay (defaulting to 30s
+// when unset, so a caller that forgets to set it can't backoff forever or
+// overflow the shift into a negative duration).
+func retryDelay(cfg retryConfig, attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	base := cfg.baseDelay
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	maxDelay := cfg.maxDelay
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+	// Shifting base by a large attempt count can overflow time.Duration
+	// (int64) to a negative or zero value, so cap the shift itself rather
+	// than trusting the result of "<<" before comparing against maxDelay.
+	d := maxDelay
+	if attempt < 63 {
+		if shifted := base << uint(attempt); shifted > 0 && shifted < maxDelay {
+			d = shifted
+		}
+	}
+	// Full jitter: uniformly distributed in [0, d].
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// parseRetryAfter parses a Retry-After header, which may be a number of
+// seconds or an HTTP date. Unparseable or absent values return 0.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// doWithRetry issues requests built by newReq, retrying on transient network
+// errors and retryable status codes according to cfg. newReq is called again
+// before each attempt so the request body can be rebuilt from scratch.
+func doWithRetry(ctx context.Context, h *http.Client, cfg retryConfig, newReq func() (*http.Request, error)) (*http.Response, error) {
+	maxAttempts := cfg.maxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		res, err := h.Do(req)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		} else if !isRetryableStatus(res.StatusCode) {
+			return res, nil
+		} else {
+			retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+			lastErr = errors.Errorf("%s %s returned retryable status %d", req.Method, req.URL, res.StatusCode)
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if attempt == maxAttempts-1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryDelay(cfg, attempt, retryAfter)):
+		}
+	}
+	return nil, lastErr
+}
+
 type executionsClientConfig struct {
-	endpoint string
-	token    string
+	endpoint  string
+	token     string
+	transport http.RoundTripper
+	retry     retryConfig
 }
 
 type ExecutionsClientOption func(*executionsClientConfig) error
@@ -33,6 +146,26 @@ func WithExecutionsEndpoint(endpoint string) ExecutionsClientOption {
 	}
 }
 
+// WithExecutionsRoundTripper overrides the http.RoundTripper used for
+// outgoing requests, letting callers inject observability or auth
+// middleware without constructing their own *http.Client.
+func WithExecutionsRoundTripper(rt http.RoundTripper) ExecutionsClientOption {
+	return func(c *executionsClientConfig) error {
+		c.transport = rt
+		return nil
+	}
+}
+
+// WithExecutionsRetries enables retrying failed requests, making at most
+// maxAttempts attempts in all (the first one included), with exponential
+// backoff starting at base and capped at max.
+func WithExecutionsRetries(maxAttempts int, base, max time.Duration) ExecutionsClientOption {
+	return func(c *executionsClientConfig) error {
+		c.retry = retryConfig{maxAttempts: maxAttempts, baseDelay: base, maxDelay: max}
+		return nil
+	}
+}
+
 func validateEndpoint(endpoint string) error {
 	_, err := url.Parse(endpoint)
 	if err != nil {
@@ -42,7 +175,7 @@ func validateEndpoint(endpoint string) error {
 }
 
 func defaultExecutionsClientOptions() *executionsClientConfig {
-	return &executionsClientConfig{}
+	return &executionsClientConfig{retry: defaultRetryConfig()}
 }
 
 // ExecutionsClient is a client for the integrations executions service
@@ -50,6 +183,7 @@ type ExecutionsClient struct {
 	http     *http.Client
 	endpoint string
 	token    string
+	retry    retryConfig
 }
 
 // NewExecutionsClient creates a new executions client
@@ -62,10 +196,19 @@ func NewExecutionsClient(h *http.Client, opts ...ExecutionsClientOption) (*Execu
 			return nil, errors.WithMessage(err, "could not create executions client")
 		}
 	}
+
+	httpClient := h
+	if cfg.transport != nil {
+		c := *h
+		c.Transport = cfg.transport
+		httpClient = &c
+	}
+
 	return &ExecutionsClient{
-		http:     h,
+		http:     httpClient,
 		endpoint: cfg.endpoint,
 		token:    cfg.token,
+		retry:    cfg.retry,
 	}, nil
 }
 
@@ -77,28 +220,29 @@ func WithExecutionsToken(token string) ExecutionsClientOption {
 }
 
 // CreateExecution creates a new execution in the executions service and returns the execution ID.
-func (c *ExecutionsClient) CreateExecution(_ context.Context, flow []byte) (string, error) {
-	reader := bytes.NewReader(flow)
+func (c *ExecutionsClient) CreateExecution(ctx context.Context, flow []byte) (string, error) {
 	path := "executions"
 	endpoint, err := url.JoinPath(c.endpoint, path)
 	if err != nil {
 		return "", errors.WithMessagef(err, "could not create full path with %q and %q", c.endpoint, path)
 	}
 
-	req, err := http.NewRequest("POST", endpoint, reader)
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Add("Content-Type", "application/json")
-	if c.token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
-	}
-
-	res, err := c.http.Do(req)
+	res, err := doWithRetry(ctx, c.http, c.retry, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(flow))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Content-Type", "application/json")
+		if c.token != "" {
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+		}
+		return req, nil
+	})
 	if err != nil {
 		return "", errors.WithMessage(err, "could not create execution")
 	}
+	defer res.Body.Close()
+
 	if res.StatusCode != http.StatusAccepted {
 		return "", errors.WithMessagef(executionsApiError, "POST %s returned %d", endpoint, res.StatusCode)
 	}
@@ -106,18 +250,22 @@ func (c *ExecutionsClient) CreateExecution(_ context.Context, flow []byte) (stri
 		ExecutionId string `json:"executionId"`
 	}
 
-	var e CreateExecutionResponse
 	bt, err := io.ReadAll(res.Body)
-	err = json.Unmarshal(bt, &e)
 	if err != nil {
+		return "", errors.WithMessage(err, "could not read execution response body")
+	}
+	var e CreateExecutionResponse
+	if err = json.Unmarshal(bt, &e); err != nil {
 		return "", errors.WithMessage(err, "could not deserialize execution body as JSON")
 	}
 	return e.ExecutionId, nil
 }
 
 type templatesClientConfig struct {
-	endpoint string
-	token    string
+	endpoint  string
+	token     string
+	transport http.RoundTripper
+	retry     retryConfig
 }
 
 type TemplatesClientOption func(*templatesClientConfig) error
@@ -139,14 +287,35 @@ func WithTemplatesToken(token string) TemplatesClientOption {
 	}
 }
 
+// WithTemplatesRoundTripper overrides the http.RoundTripper used for
+// outgoing requests, letting callers inject observability or auth
+// middleware without constructing their own *http.Client.
+func WithTemplatesRoundTripper(rt http.RoundTripper) TemplatesClientOption {
+	return func(c *templatesClientConfig) error {
+		c.transport = rt
+		return nil
+	}
+}
+
+// WithTemplatesRetries enables retrying failed requests, making at most
+// maxAttempts attempts in all (the first one included), with exponential
+// backoff starting at base and capped at max.
+func WithTemplatesRetries(maxAttempts int, base, max time.Duration) TemplatesClientOption {
+	return func(c *templatesClientConfig) error {
+		c.retry = retryConfig{maxAttempts: maxAttempts, baseDelay: base, maxDelay: max}
+		return nil
+	}
+}
+
 func defaultTemplatesClientOptions() *templatesClientConfig {
-	return &templatesClientConfig{}
+	return &templatesClientConfig{retry: defaultRetryConfig()}
 }
 
 type TemplatesClient struct {
 	http     *http.Client
 	endpoint string
 	token    string
+	retry    retryConfig
 }
 
 func NewTemplatesClient(h *http.Client, opts ...TemplatesClientOption) (*TemplatesClient, error) {
@@ -157,40 +326,55 @@ func NewTemplatesClient(h *http.Client, opts ...TemplatesClientOption) (*Templat
 			return nil, errors.WithMessage(err, "could not create templates client")
 		}
 	}
+
+	httpClient := h
+	if cfg.transport != nil {
+		c := *h
+		c.Transport = cfg.transport
+		httpClient = &c
+	}
+
 	return &TemplatesClient{
-		http:     h,
+		http:     httpClient,
 		endpoint: cfg.endpoint,
 		token:    cfg.token,
+		retry:    cfg.retry,
 	}, nil
 }
 
-func (c *TemplatesClient) RenderTemplate(_ context.Context, name string, args map[string]string) ([]byte, error) {
+func (c *TemplatesClient) RenderTemplate(ctx context.Context, name string, args map[string]string) ([]byte, error) {
 	path := "templates/%s/render"
 
 	endpoint, err := url.JoinPath(c.endpoint, fmt.Sprintf(path, name))
-
-	b, _ := json.Marshal(args)
-	// We should e able to marshal every map[string]string
-
-	br := bytes.NewReader(b)
-
-	req, err := http.NewRequest("POST", endpoint, br)
 	if err != nil {
-		return nil, err
+		return nil, errors.WithMessagef(err, "could not create full path with %q and %q", c.endpoint, name)
 	}
-	req.Header.Add("Content-Type", "application/json")
-	if c.token != "" {
-		req.Header.Add("Authorization", "Bearer "+c.token)
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not marshal args for template %q", name)
 	}
 
-	r, err := c.http.Do(req)
+	res, err := doWithRetry(ctx, c.http, c.retry, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Content-Type", "application/json")
+		if c.token != "" {
+			req.Header.Add("Authorization", "Bearer "+c.token)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, errors.WithMessagef(err, "could not get template %q", name)
 	}
-	if r.StatusCode != http.StatusOK {
-		return nil, errors.WithMessagef(templatesApiError, "got status code %d while attempting to render tempalte %q", r.StatusCode, name)
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.WithMessagef(templatesApiError, "got status code %d while attempting to render tempalte %q", res.StatusCode, name)
 	}
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "could not read template %q response body", name)
 	}
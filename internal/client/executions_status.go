@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ExecutionState is the lifecycle state of an execution.
+type ExecutionState string
+
+const (
+	ExecutionStatePending   ExecutionState = "pending"
+	ExecutionStateRunning   ExecutionState = "running"
+	ExecutionStateSucceeded ExecutionState = "succeeded"
+	ExecutionStateFailed    ExecutionState = "failed"
+	ExecutionStateCanceled  ExecutionState = "canceled"
+)
+
+// Terminal reports whether s is a state WaitForExecution should stop polling at.
+func (s ExecutionState) Terminal() bool {
+	switch s {
+	case ExecutionStateSucceeded, ExecutionStateFailed, ExecutionStateCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExecutionStatus is the current state of an execution.
+type ExecutionStatus struct {
+	ExecutionId string         `json:"executionId"`
+	State       ExecutionState `json:"state"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// GetExecution fetches the current status of the execution with the given id.
+func (c *ExecutionsClient) GetExecution(ctx context.Context, id string) (*ExecutionStatus, error) {
+	path := fmt.Sprintf("executions/%s", id)
+	endpoint, err := url.JoinPath(c.endpoint, path)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not create full path with %q and %q", c.endpoint, path)
+	}
+
+	res, err := doWithRetry(ctx, c.http, c.retry, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.token != "" {
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not get execution %q", id)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.WithMessagef(executionsApiError, "GET %s returned %d", endpoint, res.StatusCode)
+	}
+
+	var status ExecutionStatus
+	if err = json.NewDecoder(res.Body).Decode(&status); err != nil {
+		return nil, errors.WithMessage(err, "could not deserialize execution status as JSON")
+	}
+	return &status, nil
+}
+
+// waitConfig controls WaitForExecution's polling cadence.
+type waitConfig struct {
+	interval    time.Duration
+	backoff     float64
+	maxInterval time.Duration
+}
+
+func defaultWaitConfig() waitConfig {
+	return waitConfig{interval: 2 * time.Second, backoff: 1, maxInterval: 2 * time.Second}
+}
+
+// WaitOption configures WaitForExecution's polling.
+type WaitOption func(*waitConfig)
+
+// WithWaitInterval sets the initial delay between polls. Defaults to 2s.
+func WithWaitInterval(interval time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.interval = interval
+		if c.maxInterval < interval {
+			c.maxInterval = interval
+		}
+	}
+}
+
+// WithWaitBackoff multiplies the poll interval by factor after every poll,
+// up to max. A factor of 1 (the default) polls at a constant interval.
+func WithWaitBackoff(factor float64, max time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.backoff = factor
+		c.maxInterval = max
+	}
+}
+
+// WaitForExecution polls GetExecution until id reaches a terminal state or ctx is done, returning the last
+// status observed either way.
+func (c *ExecutionsClient) WaitForExecution(ctx context.Context, id string, opts ...WaitOption) (*ExecutionStatus, error) {
+	cfg := defaultWaitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	interval := cfg.interval
+	var last *ExecutionStatus
+	for {
+		status, err := c.GetExecution(ctx, id)
+		if err != nil {
+			return last, err
+		}
+		last = status
+		if status.State.Terminal() {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if cfg.backoff > 1 {
+			interval = time.Duration(float64(interval) * cfg.backoff)
+			if interval > cfg.maxInterval {
+				interval = cfg.maxInterval
+			}
+		}
+	}
+}
+
+// ExecutionFilter narrows ListExecutions by template, bot and/or creation time range. Zero fields are
+// unfiltered.
+type ExecutionFilter struct {
+	TemplateName string
+	BotId        string
+	Since        time.Time
+	Until        time.Time
+}
+
+func (f ExecutionFilter) values() url.Values {
+	q := url.Values{}
+	if f.TemplateName != "" {
+		q.Set("templateName", f.TemplateName)
+	}
+	if f.BotId != "" {
+		q.Set("botId", f.BotId)
+	}
+	if !f.Since.IsZero() {
+		q.Set("since", f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		q.Set("until", f.Until.Format(time.RFC3339))
+	}
+	return q
+}
+
+// ListExecutions queries the executions service for executions matching filter.
+func (c *ExecutionsClient) ListExecutions(ctx context.Context, filter ExecutionFilter) ([]ExecutionStatus, error) {
+	path := "executions"
+	endpoint, err := url.JoinPath(c.endpoint, path)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not create full path with %q and %q", c.endpoint, path)
+	}
+	if q := filter.values().Encode(); q != "" {
+		endpoint += "?" + q
+	}
+
+	res, err := doWithRetry(ctx, c.http, c.retry, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.token != "" {
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not list executions")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.WithMessagef(executionsApiError, "GET %s returned %d", endpoint, res.StatusCode)
+	}
+
+	type listExecutionsResponse struct {
+		Executions []ExecutionStatus `json:"executions"`
+	}
+	var lr listExecutionsResponse
+	if err = json.NewDecoder(res.Body).Decode(&lr); err != nil {
+		return nil, errors.WithMessage(err, "could not deserialize executions list as JSON")
+	}
+	return lr.Executions, nil
+}
@@ -0,0 +1,105 @@
+// Package config loads a per-customer ingestion descriptor, so onboarding a
+// new customer is a matter of adding a YAML file rather than changing Go
+// code.
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultMaxFileSize matches the original Bepensa command's hard-coded part
+// size.
+const defaultMaxFileSize = 10 * 1 << 20 // 10 MB
+
+const defaultDateFormat = "20060102"
+
+// Descriptor is one customer's ingestion config: where to read files from,
+// where to write parts to, and how to template and filter them.
+type Descriptor struct {
+	// InAuthFile is the service-account / access-key file used to read
+	// InBucket. OutBucket uses the default storage credentials.
+	InAuthFile string `yaml:"inAuthFile" json:"inAuthFile"`
+	InBucket   string `yaml:"inBucket" json:"inBucket"`
+	OutBucket  string `yaml:"outBucket" json:"outBucket"`
+
+	// RootFolder is the prefix under which source files are found.
+	RootFolder string `yaml:"rootFolder" json:"rootFolder"`
+	// DateGlob is a MatchGlob pattern with a "{date}" placeholder, filled in
+	// with today's date formatted as DateFormat.
+	DateGlob string `yaml:"dateGlob" json:"dateGlob"`
+	// DateFormat is a Go reference-time layout. Defaults to "20060102".
+	DateFormat string `yaml:"dateFormat" json:"dateFormat"`
+
+	// MaxFileSize is the approximate size, in bytes, of each split part.
+	// Defaults to 10 MB.
+	MaxFileSize int64 `yaml:"maxFileSize" json:"maxFileSize"`
+	// Filters keeps only the rows that match every entry.
+	Filters []Filter `yaml:"filters" json:"filters"`
+
+	// TemplateName is the integrations-templates template to render for
+	// each part.
+	TemplateName string `yaml:"templateName" json:"templateName"`
+	// TemplateArgs are merged with the per-part "bucket" and "file" args
+	// before rendering TemplateName.
+	TemplateArgs map[string]string `yaml:"templateArgs" json:"templateArgs"`
+
+	// CheckpointObject is the manifest object, under OutBucket, tracking
+	// which parts have already been ingested. Defaults to
+	// "<RootFolder>-checkpoint.json".
+	CheckpointObject string `yaml:"checkpointObject" json:"checkpointObject"`
+}
+
+// Glob returns DateGlob with "{date}" substituted for now, formatted per
+// DateFormat (or "20060102" if unset).
+func (d *Descriptor) Glob(now time.Time) string {
+	format := d.DateFormat
+	if format == "" {
+		format = defaultDateFormat
+	}
+	return strings.ReplaceAll(d.DateGlob, "{date}", now.Format(format))
+}
+
+// Checkpoint returns CheckpointObject, or its default derived from
+// RootFolder if unset.
+func (d *Descriptor) Checkpoint() string {
+	if d.CheckpointObject != "" {
+		return d.CheckpointObject
+	}
+	return d.RootFolder + "-checkpoint.json"
+}
+
+// Load reads a customer descriptor from a YAML or JSON file. The format is
+// chosen by extension: ".json" is JSON, anything else (including ".yaml"
+// and ".yml") is YAML.
+func Load(path string) (*Descriptor, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not read config %q", path)
+	}
+
+	d := &Descriptor{MaxFileSize: defaultMaxFileSize}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(b, d)
+	} else {
+		err = yaml.Unmarshal(b, d)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not parse config %q", path)
+	}
+	if d.MaxFileSize <= 0 {
+		d.MaxFileSize = defaultMaxFileSize
+	}
+	for i := range d.Filters {
+		if err = d.Filters[i].compile(); err != nil {
+			return nil, errors.WithMessagef(err, "could not load config %q", path)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FilterOp is a comparison applied to a single CSV column.
+type FilterOp string
+
+const (
+	FilterOpGt    FilterOp = "gt"
+	FilterOpEq    FilterOp = "eq"
+	FilterOpNe    FilterOp = "ne"
+	FilterOpRegex FilterOp = "regex"
+)
+
+// Filter keeps a CSV row only if the named or indexed Column satisfies Op
+// against Value. For FilterOpRegex, Value is compiled into re once, by
+// Load, so that Match can be called concurrently from the --workers pool
+// without racing on re.
+type Filter struct {
+	Column string   `yaml:"column" json:"column"`
+	Op     FilterOp `yaml:"op" json:"op"`
+	Value  string   `yaml:"value" json:"value"`
+
+	re *regexp.Regexp
+}
+
+// compile precompiles re for FilterOpRegex filters, so Match never mutates
+// shared state at request time.
+func (f *Filter) compile() error {
+	if f.Op != FilterOpRegex {
+		return nil
+	}
+	re, err := regexp.Compile(f.Value)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid regex %q", f.Value)
+	}
+	f.re = re
+	return nil
+}
+
+// columnIndex resolves Column to a position in record, trying it as a
+// 0-based index first and falling back to a case-insensitive header name.
+func (f *Filter) columnIndex(header []string) (int, error) {
+	if i, err := strconv.Atoi(f.Column); err == nil {
+		if i < 0 || i >= len(header) {
+			return 0, errors.Errorf("column index %d out of range for header of length %d", i, len(header))
+		}
+		return i, nil
+	}
+	for i, h := range header {
+		if strings.EqualFold(h, f.Column) {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("column %q not found in header", f.Column)
+}
+
+// Match reports whether record satisfies the filter, given the file's
+// header to resolve a named column.
+func (f *Filter) Match(header, record []string) (bool, error) {
+	i, err := f.columnIndex(header)
+	if err != nil {
+		return false, err
+	}
+	cell := record[i]
+
+	switch f.Op {
+	case FilterOpEq:
+		return cell == f.Value, nil
+	case FilterOpNe:
+		return cell != f.Value, nil
+	case FilterOpGt:
+		cellVal, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return false, errors.WithMessagef(err, "could not convert column %q value %q to float", f.Column, cell)
+		}
+		wantVal, err := strconv.ParseFloat(f.Value, 64)
+		if err != nil {
+			return false, errors.WithMessagef(err, "could not convert filter value %q to float", f.Value)
+		}
+		// Avoid strict equality due to floating point errors.
+		return cellVal-wantVal > 0.0001, nil
+	case FilterOpRegex:
+		if f.re == nil {
+			return false, errors.Errorf("regex filter on column %q was not compiled", f.Column)
+		}
+		return f.re.MatchString(cell), nil
+	default:
+		return false, errors.Errorf("unknown filter op %q", f.Op)
+	}
+}
+
+// MatchAll reports whether record satisfies every filter in filters.
+func MatchAll(filters []Filter, header, record []string) (bool, error) {
+	for i := range filters {
+		ok, err := filters[i].Match(header, record)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fake is an in-memory Storage for unit tests, so callers like splitFile can
+// be exercised without a network-backed driver.
+type Fake struct {
+	mu      sync.Mutex
+	objects map[string]map[string][]byte // bucket -> name -> contents
+}
+
+// NewFake returns an empty in-memory Storage.
+func NewFake() *Fake {
+	return &Fake{objects: map[string]map[string][]byte{}}
+}
+
+// Seed pre-populates bucket/name with contents, for use in test setup.
+func (f *Fake) Seed(bucket, name string, contents []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.objects[bucket] == nil {
+		f.objects[bucket] = map[string][]byte{}
+	}
+	f.objects[bucket][name] = contents
+}
+
+// Get returns the current contents of bucket/name, for use in test
+// assertions after a write.
+func (f *Fake) Get(bucket, name string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.objects[bucket][name]
+	return b, ok
+}
+
+func (f *Fake) List(_ context.Context, bucket string, query *Query) ([]Object, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var names []string
+	for name := range f.objects[bucket] {
+		if strings.HasPrefix(name, query.Prefix) && matchGlob(query.MatchGlob, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	objects := make([]Object, len(names))
+	for i, name := range names {
+		objects[i] = Object{Name: name}
+	}
+	return objects, nil
+}
+
+func (f *Fake) NewReader(_ context.Context, bucket, name string) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[bucket][name]
+	if !ok {
+		return nil, ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *Fake) NewWriter(_ context.Context, bucket, name string) (io.WriteCloser, error) {
+	return &fakeWriter{fake: f, bucket: bucket, name: name}, nil
+}
+
+type fakeWriter struct {
+	fake   *Fake
+	bucket string
+	name   string
+	buf    bytes.Buffer
+}
+
+func (w *fakeWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *fakeWriter) Close() error {
+	w.fake.Seed(w.bucket, w.name, w.buf.Bytes())
+	return nil
+}
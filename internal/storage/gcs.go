@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+type gcsStorage struct {
+	client *storage.Client
+}
+
+func newGCS(ctx context.Context, opts Options) (Storage, error) {
+	var clientOpts []option.ClientOption
+	if opts.CredentialsFile != "" {
+		clientOpts = append(clientOpts, option.WithCredentialsFile(opts.CredentialsFile))
+	}
+	client, err := storage.NewClient(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsStorage{client: client}, nil
+}
+
+func (s *gcsStorage) List(ctx context.Context, bucket string, query *Query) ([]Object, error) {
+	it := s.client.Bucket(bucket).Objects(ctx, &storage.Query{
+		Prefix:    query.Prefix,
+		MatchGlob: query.MatchGlob,
+		Delimiter: query.Delimiter,
+	})
+
+	var objects []Object
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, Object{Name: attrs.Name})
+	}
+	return objects, nil
+}
+
+func (s *gcsStorage) NewReader(ctx context.Context, bucket, name string) (io.ReadCloser, error) {
+	r, err := s.client.Bucket(bucket).Object(name).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, ErrNotExist
+	}
+	return r, err
+}
+
+func (s *gcsStorage) NewWriter(ctx context.Context, bucket, name string) (io.WriteCloser, error) {
+	return s.client.Bucket(bucket).Object(name).NewWriter(ctx), nil
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"path"
+	"strings"
+)
+
+// matchGlob reports whether name matches pattern, which may be empty (match
+// everything) or carry a leading "**/" as GCS's MatchGlob does to mean "any
+// directory depth". Drivers whose backend doesn't support MatchGlob natively
+// (S3, OSS, local, fake) use this to filter List results client-side.
+func matchGlob(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	p := strings.TrimPrefix(pattern, "**/")
+	base := name
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		base = name[idx+1:]
+	}
+	ok, _ := path.Match(p, base)
+	return ok
+}
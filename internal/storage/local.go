@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// localStorage is a Storage backed by a directory on the local filesystem,
+// laid out as <RootDir>/<bucket>/<name>. It exists mainly so commands can be
+// exercised end-to-end without a real cloud bucket.
+type localStorage struct {
+	root string
+}
+
+func newLocal(opts Options) (Storage, error) {
+	return &localStorage{root: opts.RootDir}, nil
+}
+
+func (s *localStorage) path(bucket, name string) string {
+	return filepath.Join(s.root, bucket, filepath.FromSlash(name))
+}
+
+func (s *localStorage) List(_ context.Context, bucket string, query *Query) ([]Object, error) {
+	base := filepath.Join(s.root, bucket)
+	root := filepath.Join(base, filepath.FromSlash(query.Prefix))
+
+	var objects []Object
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if matchGlob(query.MatchGlob, name) {
+			objects = append(objects, Object{Name: name})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func (s *localStorage) NewReader(_ context.Context, bucket, name string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(bucket, name))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return f, err
+}
+
+func (s *localStorage) NewWriter(_ context.Context, bucket, name string) (io.WriteCloser, error) {
+	p := s.path(bucket, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(p)
+}
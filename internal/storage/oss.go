@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/denverdino/aliyungo/oss"
+	"github.com/pkg/errors"
+)
+
+// ossCredentials is the shape of the Aliyun access-key JSON file pointed to
+// by Options.CredentialsFile.
+type ossCredentials struct {
+	AccessKeyId     string `json:"accessKeyId"`
+	AccessKeySecret string `json:"accessKeySecret"`
+}
+
+func loadOSSCredentials(path string) (ossCredentials, error) {
+	var creds ossCredentials
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return creds, errors.WithMessagef(err, "could not read OSS credentials file %q", path)
+	}
+	if err = json.Unmarshal(b, &creds); err != nil {
+		return creds, errors.WithMessagef(err, "could not parse OSS credentials file %q", path)
+	}
+	return creds, nil
+}
+
+type ossStorage struct {
+	client *oss.Client
+}
+
+func newOSS(_ context.Context, opts Options) (Storage, error) {
+	creds, err := loadOSSCredentials(opts.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	client := oss.NewOSSClient(opts.Region, false, creds.AccessKeyId, creds.AccessKeySecret, true)
+	return &ossStorage{client: client}, nil
+}
+
+func (s *ossStorage) List(_ context.Context, bucket string, query *Query) ([]Object, error) {
+	var objects []Object
+	marker := ""
+	for {
+		resp, err := s.client.Bucket(bucket).List(query.Prefix, query.Delimiter, marker, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range resp.Contents {
+			if matchGlob(query.MatchGlob, c.Key) {
+				objects = append(objects, Object{Name: c.Key})
+			}
+		}
+		if !resp.IsTruncated {
+			break
+		}
+		marker = resp.NextMarker
+	}
+	return objects, nil
+}
+
+func (s *ossStorage) NewReader(_ context.Context, bucket, name string) (io.ReadCloser, error) {
+	data, err := s.client.Bucket(bucket).Get(name)
+	if err != nil {
+		if ossErr, ok := err.(*oss.Error); ok && ossErr.StatusCode == 404 {
+			return nil, ErrNotExist
+		}
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *ossStorage) NewWriter(_ context.Context, bucket, name string) (io.WriteCloser, error) {
+	return &ossWriter{bucket: s.client.Bucket(bucket), key: name}, nil
+}
+
+// ossWriter buffers writes in memory and uploads the object with a single
+// Put call on Close, mirroring how the aliyungo/oss client is used
+// elsewhere in our customer integrations.
+type ossWriter struct {
+	bucket *oss.Bucket
+	key    string
+	buf    bytes.Buffer
+}
+
+func (w *ossWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *ossWriter) Close() error {
+	return w.bucket.Put(w.key, w.buf.Bytes(), "text/csv", oss.Private)
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	stderrs "errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type s3Storage struct {
+	client *s3.Client
+}
+
+func newS3(ctx context.Context, opts Options) (Storage, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(opts.Region))
+	if err != nil {
+		return nil, err
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if opts.Endpoint != "" {
+			o.BaseEndpoint = aws.String(opts.Endpoint)
+		}
+	})
+	return &s3Storage{client: client}, nil
+}
+
+// List pages through ListObjectsV2 and filters client-side by MatchGlob,
+// since S3 has no native glob support.
+func (s *s3Storage) List(ctx context.Context, bucket string, query *Query) ([]Object, error) {
+	var objects []Object
+	var token *string
+	for {
+		out, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(bucket),
+			Prefix:            aws.String(query.Prefix),
+			Delimiter:         aws.String(query.Delimiter),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range out.Contents {
+			name := aws.ToString(obj.Key)
+			if matchGlob(query.MatchGlob, name) {
+				objects = append(objects, Object{Name: name})
+			}
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		token = out.NextContinuationToken
+	}
+	return objects, nil
+}
+
+func (s *s3Storage) NewReader(ctx context.Context, bucket, name string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	})
+	var noSuchKey *types.NoSuchKey
+	if stderrs.As(err, &noSuchKey) {
+		return nil, ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3Storage) NewWriter(ctx context.Context, bucket, name string) (io.WriteCloser, error) {
+	return &s3Writer{ctx: ctx, client: s.client, bucket: bucket, key: name}, nil
+}
+
+// s3Writer buffers writes in memory and uploads the object as a single
+// PutObject call on Close, since S3 has no append/stream-write API.
+type s3Writer struct {
+	ctx    context.Context
+	client *s3.Client
+	bucket string
+	key    string
+	buf    bytes.Buffer
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	_, err := w.client.PutObject(w.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(w.key),
+		Body:   bytes.NewReader(w.buf.Bytes()),
+	})
+	return err
+}
@@ -0,0 +1,86 @@
+// Package storage abstracts the object storage operations used by the
+// ingestion pipeline so that commands can target GCS, S3, Aliyun OSS, the
+// local filesystem, or an in-memory fake, by config alone.
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotExist is returned by NewReader when the requested object does not
+// exist, regardless of which driver is in use.
+var ErrNotExist = errors.New("storage: object does not exist")
+
+// Object describes a single object returned by a List call.
+type Object struct {
+	Name string
+}
+
+// Query narrows a List call to objects under Prefix matching MatchGlob, with
+// objects grouped into "directories" at Delimiter boundaries. It mirrors the
+// fields of cloud.google.com/go/storage.Query that the pipeline relies on.
+// The local and fake drivers ignore Delimiter and list every object under
+// Prefix, at any depth.
+type Query struct {
+	Prefix    string
+	MatchGlob string
+	Delimiter string
+}
+
+// Storage is an object storage backend: enough of one to list, read and
+// write whole objects.
+type Storage interface {
+	List(ctx context.Context, bucket string, query *Query) ([]Object, error)
+	NewReader(ctx context.Context, bucket, name string) (io.ReadCloser, error)
+	NewWriter(ctx context.Context, bucket, name string) (io.WriteCloser, error)
+}
+
+// Driver names a Storage implementation, selectable via config (e.g. the
+// STORAGE_IN_DRIVER / STORAGE_OUT_DRIVER environment variables).
+type Driver string
+
+const (
+	DriverGCS   Driver = "gcs"
+	DriverS3    Driver = "s3"
+	DriverOSS   Driver = "oss"
+	DriverLocal Driver = "local"
+	DriverFake  Driver = "fake"
+)
+
+// Options holds the union of settings needed by any driver. Each driver
+// reads only the fields it needs.
+type Options struct {
+	// CredentialsFile is a service-account or access-key JSON file, used by
+	// the gcs and oss drivers. The s3 driver does not read it and takes its
+	// credentials from the default AWS configuration.
+	CredentialsFile string
+	// Region is the S3 / OSS region.
+	Region string
+	// Endpoint overrides the default S3 endpoint, e.g. for S3-compatible
+	// services. The oss driver does not read it.
+	Endpoint string
+	// RootDir is the base directory for the local driver.
+	RootDir string
+}
+
+// New constructs the Storage backend named by driver. An empty driver
+// defaults to DriverGCS, matching this project's original behavior.
+func New(ctx context.Context, driver Driver, opts Options) (Storage, error) {
+	switch driver {
+	case DriverGCS, "":
+		return newGCS(ctx, opts)
+	case DriverS3:
+		return newS3(ctx, opts)
+	case DriverOSS:
+		return newOSS(ctx, opts)
+	case DriverLocal:
+		return newLocal(opts)
+	case DriverFake:
+		return NewFake(), nil
+	default:
+		return nil, errors.Errorf("unknown storage driver %q", driver)
+	}
+}